@@ -0,0 +1,81 @@
+package gui
+
+import (
+	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/miner"
+	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/scheduler"
+	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/stats"
+)
+
+// Config holds the persisted configuration for the GUI miner
+type Config struct {
+	APIEndpoint  string
+	CoinType     string
+	CoinAlgo     string
+	XmrigAlgo    string
+	XmrigVariant string
+	HardwareType int
+	Mid          string
+	Address      string
+	// Pools is the ordered, priority-sorted list of pools configureMiner
+	// resolved and wrote to the backend. Pools[0] is the one currently in
+	// use; the rest are automatic failover candidates
+	Pools []miner.PoolChoice
+	Miner miner.Config
+	// RPC holds the configuration for the optional local control API
+	RPC RPCConfig
+	// Schedule holds the user-configured mining schedule, e.g. excluded
+	// hours or on-battery pausing
+	Schedule scheduler.Schedule
+	// Stats holds the lifetime hashrate/share totals, carried across restarts
+	Stats stats.Totals
+	// Metrics holds the configuration for the optional Prometheus exporter
+	Metrics MetricsConfig
+	// Failover controls automatic pool failover when the active pool is
+	// unreachable or rejecting too many shares
+	Failover FailoverConfig
+}
+
+// FailoverConfig controls the health monitor that hot-swaps the active pool
+// when it looks unhealthy. Off by default since not every user wants pools
+// switched out from under them
+type FailoverConfig struct {
+	// Enabled turns on automatic pool failover
+	Enabled bool
+	// UnreachableSeconds is how long the active pool's API must be
+	// unreachable before failing over to the next pool in Config.Pools
+	UnreachableSeconds int
+	// RejectPercent is the share reject rate, out of 100, that triggers a
+	// failover even while the active pool is still reachable. 0 disables
+	// the reject-rate check
+	RejectPercent float64
+}
+
+// MetricsConfig controls the opt-in Prometheus metrics endpoint
+type MetricsConfig struct {
+	// Listen is the host:port the exporter's HTTP server binds to, e.g.
+	// "127.0.0.1:9090". Leave empty to disable the exporter
+	Listen string
+}
+
+// RPCConfig controls the opt-in local JSON-RPC / IPC control API that lets
+// headless scripts, dashboards or a systemd unit drive the miner without the
+// Electron window
+type RPCConfig struct {
+	// Enabled turns the RPC server on. It is off by default.
+	Enabled bool
+	// BindAddress is the host:port the HTTP+JSON-RPC listener binds to,
+	// e.g. "127.0.0.1:4783". Leave empty to disable the HTTP transport.
+	BindAddress string
+	// SocketPath is the path to the Unix socket (or Windows named pipe) the
+	// IPC transport listens on. Leave empty to disable the IPC transport.
+	SocketPath string
+	// AuthToken, when set, must be supplied by callers as a Bearer token
+	// (HTTP) or as the "auth" field of the first IPC request.
+	AuthToken string
+	// AllowedOrigins restricts which Origin headers the HTTP transport will
+	// accept. An empty list allows any origin.
+	AllowedOrigins []string
+	// EnabledMethods whitelists which RPC methods may be called. An empty
+	// list enables every method the server knows about.
+	EnabledMethods []string
+}
@@ -14,9 +14,12 @@ import (
 
 	astilectron "github.com/asticode/go-astilectron"
 	bootstrap "github.com/asticode/go-astilectron-bootstrap"
+	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/metrics"
+	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/miner"
+	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/scheduler"
+	gstats "github.com/furiousteam/BLOC-GUI-Miner/src/gui/stats"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-	"github.com/furiousteam/BLOC-GUI-Miner/src/gui/miner"
 )
 
 // GUI implements the core control for the GUI miner
@@ -35,16 +38,43 @@ type GUI struct {
 	logger *logrus.Entry
 	// workingDir holds the current working directory
 	workingDir string
-	// currentHashrate of the user if mining
-	lastHashrate float64
+	// recorder keeps rolling hashrate/share statistics for the running miner
+	recorder *gstats.Recorder
 	// miningStatsTicker controls the interval for fetching mining stats from
 	// the selected miner
 	miningStatsTicker *time.Ticker
 	// networkStatsTicker controls the interval for fetching network, trading
 	// and other stats
 	networkStatsTicker *time.Ticker
+	// summaryTicker controls the interval for logging and broadcasting the
+	// rolling stats summary
+	summaryTicker *time.Ticker
+	// rpc is the opt-in local control API, nil unless Config.RPC.Enabled
+	rpc *rpcServer
+	// metrics is the opt-in Prometheus exporter, nil unless Config.Metrics.Listen is set
+	metrics *metrics.Exporter
+	// scheduler decides whether the miner should be running right now
+	scheduler *scheduler.Scheduler
+	// minerState is the last MinerState reconcileLoop reported to the front-end
+	minerState scheduler.State
+	// reconcileTicker drives reconcileLoop
+	reconcileTicker *time.Ticker
+	// failoverTicker drives the pool health monitor
+	failoverTicker *time.Ticker
+	// poolUnreachableSince is when the active pool's API last started
+	// failing to respond, zero while it's reachable
+	poolUnreachableSince time.Time
+	// poolActiveBaseline is the lifetime share totals recorded when the
+	// current pool became active, so checkFailover's reject-rate trigger is
+	// scoped to "since this pool took over" instead of the whole process
+	// lifetime, which would otherwise stay tripped right after a failover
+	poolActiveBaseline gstats.Totals
 }
 
+// failoverCheckInterval is how often checkFailover evaluates whether the
+// active pool should be swapped out for the next one in Config.Pools
+const failoverCheckInterval = time.Second * 10
+
 // New creates a new instance of the miner application
 func New(
 	appName string,
@@ -64,9 +94,9 @@ func New(
 	}
 
 	gui := GUI{
-		config:       config,
-		workingDir:   workingDir,
-		inDebugMode:  isDebug,
+		config:      config,
+		workingDir:  workingDir,
+		inDebugMode: isDebug,
 	}
 
 	// If no config is specified then this is the first run
@@ -93,6 +123,12 @@ func New(
 			Mid:          uuid.New().String(),
 		}
 	}
+	gui.scheduler = scheduler.New(gui.config.Schedule, scheduler.SystemSignals{
+		OnBattery:       onBatteryPower,
+		ForeignCPUUsage: func() (float64, error) { return foreignCPUUsage(gui.minerPid()) },
+	})
+	gui.recorder = gstats.NewRecorder(gui.config.Stats)
+	gui.metrics = metrics.NewExporter()
 	var menu []*astilectron.MenuItemOptions
 
 	// Setup the logging, by default we log to stdout
@@ -215,6 +251,28 @@ func New(
 			}()
 			// Trigger a network stats update as soon as we start
 			gui.updateNetworkStats()
+
+			gui.reconcileTicker = time.NewTicker(time.Second * 5)
+			gui.minerState = scheduler.PausedUser
+			go gui.reconcileLoop()
+
+			gui.summaryTicker = time.NewTicker(time.Second * 20)
+			go gui.logStatsSummaryLoop()
+
+			gui.failoverTicker = time.NewTicker(failoverCheckInterval)
+			go gui.monitorFailoverLoop()
+
+			rpc, err := gui.startRPCServer(gui.config.RPC)
+			if err != nil {
+				gui.logger.Errorf("Unable to start RPC server: %s", err)
+			} else {
+				gui.rpc = rpc
+			}
+
+			if err := gui.metrics.Start(gui.config.Metrics.Listen); err != nil {
+				gui.logger.Errorf("Unable to start metrics exporter: %s", err)
+			}
+
 			// uncomment this to have development tools opened when the app is built
 			if gui.inDebugMode {
 				gui.window.OpenDevTools()
@@ -240,9 +298,137 @@ func (gui *GUI) Run() error {
 	}
 	gui.miningStatsTicker.Stop()
 	gui.networkStatsTicker.Stop()
+	gui.reconcileTicker.Stop()
+	gui.summaryTicker.Stop()
+	gui.failoverTicker.Stop()
+	gui.rpc.Stop()
+	gui.metrics.Stop()
+
+	gui.config.Stats = gui.recorder.Totals()
+	if err := gui.SaveConfig(*gui.config); err != nil {
+		gui.logger.Errorf("Unable to persist lifetime stats: %s", err)
+	}
 	return nil
 }
 
+// reconcileLoop is the single place that decides whether the miner should be
+// running. It consults the scheduler on every tick and drives
+// gui.startMiner/gui.stopMiner instead of having callers trigger them
+// directly, so every pause reason (manual, scheduled hours, battery, no
+// connection) goes through the same state machine
+func (gui *GUI) reconcileLoop() {
+	for range gui.reconcileTicker.C {
+		gui.reconcile()
+	}
+}
+
+// reconcile evaluates the scheduler once and, if the desired state changed,
+// starts or stops the miner and notifies the front-end
+func (gui *GUI) reconcile() {
+	if gui.miner == nil {
+		return
+	}
+	desired := gui.scheduler.Evaluate()
+	if desired == gui.minerState {
+		return
+	}
+
+	wasActive := gui.minerState == scheduler.Active
+	gui.minerState = desired
+
+	if desired == scheduler.Active && !wasActive {
+		gui.startMiner()
+	} else if desired != scheduler.Active && wasActive {
+		if err := gui.stopMiner(); err != nil {
+			gui.logger.Errorf("Unable to stop miner for '%s': %s", desired, err)
+		}
+	}
+
+	gui.logger.WithField("state", desired.String()).Info("Miner state changed")
+	_ = gui.sendElectronCommand("miner_state", desired.String())
+
+	if desired == scheduler.Active {
+		gui.metrics.SetState(metrics.StateRunning)
+	} else {
+		gui.metrics.SetState(metrics.StatePaused)
+	}
+}
+
+// monitorFailoverLoop watches the active pool's health and hands off to
+// checkFailover on every tick
+func (gui *GUI) monitorFailoverLoop() {
+	for range gui.failoverTicker.C {
+		gui.checkFailover()
+	}
+}
+
+// checkFailover hot-swaps to the next pool in Config.Pools when the active
+// one has been unreachable for Config.Failover.UnreachableSeconds or its
+// reject rate exceeds Config.Failover.RejectPercent. It is a no-op unless
+// the user opted into Config.Failover.Enabled and configured more than one
+// pool
+func (gui *GUI) checkFailover() {
+	if gui.miner == nil || !gui.config.Failover.Enabled || len(gui.config.Pools) < 2 {
+		return
+	}
+
+	if _, err := gui.miner.GetStats(); err != nil {
+		if gui.poolUnreachableSince.IsZero() {
+			gui.poolUnreachableSince = time.Now()
+		}
+	} else {
+		gui.poolUnreachableSince = time.Time{}
+	}
+
+	var unreachableFor time.Duration
+	if !gui.poolUnreachableSince.IsZero() {
+		unreachableFor = time.Since(gui.poolUnreachableSince)
+	}
+	rejectPercent := gui.rejectPercentSinceActive()
+
+	unreachableTooLong := gui.config.Failover.UnreachableSeconds > 0 &&
+		unreachableFor >= time.Duration(gui.config.Failover.UnreachableSeconds)*time.Second
+	rejectingTooMuch := gui.config.Failover.RejectPercent > 0 && rejectPercent >= gui.config.Failover.RejectPercent
+	if !unreachableTooLong && !rejectingTooMuch {
+		return
+	}
+
+	current := gui.config.Pools[0]
+	next := gui.config.Pools[1]
+	gui.logger.WithFields(logrus.Fields{
+		"from": current.PoolID,
+		"to":   next.PoolID,
+	}).Warning("Pool looks unhealthy, failing over")
+
+	if err := gui.miner.Failover(next); err != nil {
+		gui.logger.Errorf("Unable to fail over to pool '%s': %s", next.PoolID, err)
+		return
+	}
+
+	gui.config.Pools = miner.ReorderPools(gui.config.Pools, next)
+	gui.poolUnreachableSince = time.Time{}
+	gui.poolActiveBaseline = gui.recorder.Totals()
+	if err := gui.SaveConfig(*gui.config); err != nil {
+		gui.logger.Errorf("Unable to persist pool failover: %s", err)
+	}
+	_ = gui.sendElectronCommand("pool_switched", next.PoolID)
+	gui.rpc.publish("pool_switched", next)
+}
+
+// rejectPercentSinceActive returns the share reject rate accumulated since
+// the current pool became active, rather than the lifetime rate, so a
+// healthy pool isn't immediately judged by rejects that happened on a pool
+// that was already failed over away from
+func (gui *GUI) rejectPercentSinceActive() float64 {
+	totals := gui.recorder.Totals()
+	accepted := totals.SharesAccepted - gui.poolActiveBaseline.SharesAccepted
+	rejected := totals.SharesRejected - gui.poolActiveBaseline.SharesRejected
+	if total := accepted + rejected; total > 0 {
+		return float64(rejected) / float64(total) * 100
+	}
+	return 0
+}
+
 // handleElectronCommands handles the messages sent by the Electron front-end
 func (gui *GUI) handleElectronCommands(
 	_ *astilectron.Window,
@@ -382,33 +568,33 @@ func (gui *GUI) handleElectronCommands(
 	// reconfigure is sent after settings are changes by the user
 	// NOTE: this function is no longer used, as the miner webpage gets reloaded (instead of calling reconfigure) when it's settings change
 	/*
-	case "reconfigure":
-		gui.logger.Info("Reconfiguring miner")
-		err := gui.stopMiner()
-		if err != nil {
-			_ = gui.sendElectronCommand("fatal_error", ElectronMessage{
-				Data: fmt.Sprintf("Unable to stop miner for reconfigure."+
-					"Please close the miner and open it again."+
-					"<br/>The error was '%s'", err),
-			})
-			// Give the UI some time to display the message
-			time.Sleep(time.Second * 15)
-			gui.logger.Fatalf("Unable to reconfigure miner: '%s'", err)
-		}
-		gui.logger.WithField(
-			"name", command.Name,
-		).Debug("Received command from Electrom")
-		gui.configureMiner(command)
-		// Fake some time to have GUI at least display the message
-		time.Sleep(time.Second * 3)
-		gui.startMiner()
-		gui.logger.Info("Miner reconfigured")
-
-		gui.lastHashrate = 0.00
-		// Trigger pool update
-		go gui.updateNetworkStats()
-
-		return "Ok", nil
+		case "reconfigure":
+			gui.logger.Info("Reconfiguring miner")
+			err := gui.stopMiner()
+			if err != nil {
+				_ = gui.sendElectronCommand("fatal_error", ElectronMessage{
+					Data: fmt.Sprintf("Unable to stop miner for reconfigure."+
+						"Please close the miner and open it again."+
+						"<br/>The error was '%s'", err),
+				})
+				// Give the UI some time to display the message
+				time.Sleep(time.Second * 15)
+				gui.logger.Fatalf("Unable to reconfigure miner: '%s'", err)
+			}
+			gui.logger.WithField(
+				"name", command.Name,
+			).Debug("Received command from Electrom")
+			gui.configureMiner(command)
+			// Fake some time to have GUI at least display the message
+			time.Sleep(time.Second * 3)
+			gui.startMiner()
+			gui.logger.Info("Miner reconfigured")
+
+			gui.lastHashrate = 0.00
+			// Trigger pool update
+			go gui.updateNetworkStats()
+
+			return "Ok", nil
 	*/
 
 	// get-config-file is sent before any other command from the index.html
@@ -420,6 +606,7 @@ func (gui *GUI) handleElectronCommands(
 			XmrigAlgo:    gui.config.XmrigAlgo,
 			XmrigVariant: gui.config.XmrigVariant,
 			HardwareType: gui.config.HardwareType,
+			MinerPath:    gui.config.Miner.Path,
 		}
 
 		dataBytes, err := json.Marshal(currentConfig)
@@ -430,28 +617,53 @@ func (gui *GUI) handleElectronCommands(
 		return string(dataBytes), nil
 
 	// start-miner is sent after configuration or when the user
-	// clicks 'start mining'
+	// clicks 'start mining'. The actual start happens in reconcile, so every
+	// pause reason goes through the same state machine
 	case "start-miner":
-		gui.startMiner()
+		gui.scheduler.SetUserPaused(false)
+		gui.reconcile()
 
 	// stop-miner is sent whenever the user clicks 'stop mining'
 	case "stop-miner":
-		err := gui.stopMiner()
+		gui.scheduler.SetUserPaused(true)
+		gui.reconcile()
+
+	// get-schedule returns the user's current mining schedule
+	case "get-schedule":
+		scheduleBytes, err := json.Marshal(gui.scheduler.Schedule())
+		if err != nil {
+			gui.logger.Errorf("Unable to send schedule to front-end: %s", err)
+			return "", nil
+		}
+		return string(scheduleBytes), nil
+
+	// set-schedule is sent whenever the user changes their mining schedule
+	case "set-schedule":
+		var newSchedule scheduler.Schedule
+		err := json.Unmarshal(command.Payload, &newSchedule)
 		if err != nil {
 			_ = gui.sendElectronCommand("fatal_error", ElectronMessage{
-				Data: fmt.Sprintf("Unable to stop miner backend."+
-					"Please close the miner and open it again."+
+				Data: fmt.Sprintf("Unable to set schedule."+
+					"Please check your schedule is valid."+
 					"<br/>The error was '%s'", err),
 			})
-			// Give the UI some time to display the message
-			time.Sleep(time.Second * 15)
-			gui.logger.Fatalf("Unable to stop the miner: '%s'", err)
+			return "", nil
 		}
+		gui.scheduler.SetSchedule(newSchedule)
+		gui.config.Schedule = newSchedule
+		if err := gui.SaveConfig(*gui.config); err != nil {
+			gui.logger.Errorf("Unable to persist schedule: %s", err)
+		}
+		gui.reconcile()
+		return "Ok", nil
 	}
 	return nil, fmt.Errorf("'%s' is an unknown command", command.Name)
 }
 
-// configureMiner creates the miner configuration to use
+// configureMiner creates the miner configuration to use. It stops whatever
+// backend is currently running before swapping it out, so it's also safe to
+// call on a live miner (e.g. via the RPC Reconfigure method) rather than
+// only on first setup
 func (gui *GUI) configureMiner(command bootstrap.MessageIn) {
 	gui.logger.Info("Configuring miner")
 
@@ -467,26 +679,75 @@ func (gui *GUI) configureMiner(command bootstrap.MessageIn) {
 		time.Sleep(time.Second * 15)
 		gui.logger.Fatalf("Unable to configure miner: '%s'", err)
 	}
+
+	// Reconfiguring a miner that's already running would otherwise orphan
+	// the old backend process (it stays alive but is no longer referenced)
+	// and risk a second process getting started alongside it. Stop it here
+	// and remember to start the new one back up once it's configured
+	wasRunning := gui.miner != nil && gui.minerState == scheduler.Active
+	if gui.miner != nil {
+		if err := gui.stopMiner(); err != nil {
+			gui.logger.Errorf("Unable to stop previous miner before reconfigure: %s", err)
+		}
+	}
+
 	// gui.logger.Info(fmt.Printf("%+v\n", newConfig))
 	gui.config.Address = newConfig.Address
-	gui.config.PoolID = newConfig.Pool
 	gui.config.CoinType = newConfig.CoinType
 	gui.config.CoinAlgo = newConfig.CoinAlgo
 	gui.config.XmrigAlgo = newConfig.XmrigAlgo
 	gui.config.XmrigVariant = newConfig.XmrigVariant
 	gui.config.HardwareType = newConfig.HardwareType
+	if newConfig.MinerPath != "" {
+		// The user picked their own miner installation from the front-end,
+		// remember it so we scan there instead of the bundled directory
+		gui.config.Miner.Path = newConfig.MinerPath
+	}
 
-	scanPath := filepath.Join(gui.workingDir, "miner")
-	// TODO: Fix own miner paths option
-	/*if gui.config.Miner.Path != "" {
-		//scanPath = path.Base(gui.config.Miner.Path)
-	}*/
-	gui.logger.WithField(
-		"scan_path", scanPath,
-	).Debug("Determining miner type")
+	// HardwareType only distinguishes CPU (1) from GPU (2); it can't express
+	// GPU vendor, so when GPU is selected try both NVIDIA and AMD and use
+	// whichever has a present, compatible executable. Otherwise GPU-AMD-only
+	// backends like teamredminer could never be selected
+	hardwareCandidates := []miner.Capability{miner.CapabilityCPU}
+	if gui.config.HardwareType == 2 {
+		hardwareCandidates = []miner.Capability{miner.CapabilityGPUNvidia, miner.CapabilityGPUAMD}
+	}
 
-	// Determine the type of miner bundled
-	minerType, minerPath, err := miner.DetermineMinerType(scanPath)
+	var minerType, minerPath string
+	if info, statErr := os.Stat(gui.config.Miner.Path); statErr == nil && !info.IsDir() {
+		// The user pointed us at a specific executable; honor it as-is
+		// rather than re-detecting it by its conventional filename
+		gui.logger.WithField(
+			"miner_path", gui.config.Miner.Path,
+		).Debug("Using user-specified miner executable")
+		minerPath = gui.config.Miner.Path
+		for _, hardware := range hardwareCandidates {
+			minerType, err = miner.SelectBackendForPath(minerPath, hardware, gui.config.CoinAlgo)
+			if err == nil {
+				break
+			}
+		}
+	} else {
+		scanPath := filepath.Join(gui.workingDir, "miner")
+		if gui.config.Miner.Path != "" {
+			// The user pointed us at their own miner installation directory,
+			// scan that instead of the bundled "miner" directory
+			scanPath = gui.config.Miner.Path
+		}
+		gui.logger.WithField(
+			"scan_path", scanPath,
+		).Debug("Determining miner type")
+
+		// Pick whichever registered backend is both compatible with the
+		// selected hardware/algo and actually present, rather than assuming a
+		// single bundled binary
+		for _, hardware := range hardwareCandidates {
+			minerType, minerPath, err = miner.SelectBackend(scanPath, hardware, gui.config.CoinAlgo)
+			if err == nil {
+				break
+			}
+		}
+	}
 	if err != nil {
 		_ = gui.sendElectronCommand("fatal_error", ElectronMessage{
 			Data: fmt.Sprintf("Unable to configure miner."+
@@ -519,33 +780,51 @@ func (gui *GUI) configureMiner(command bootstrap.MessageIn) {
 		gui.logger.Fatalf("Unable to configure miner: '%s'", err)
 	}
 
-	// The pool API returns the low-end hardware host:port config for pool
+	// The pool API returns the low-end hardware host:port config for each
+	// pool. newConfig.Pool is always tried first; newConfig.Pools (if any)
+	// are ordered automatic failover candidates
 	gui.logger.Debug("Getting pool information")
-	poolInfo, err := gui.GetPool(gui.config.PoolID)
-	if err != nil {
-		_ = gui.sendElectronCommand("fatal_error", ElectronMessage{
-			Data: fmt.Sprintf("Unable to configure miner."+
-				"Please check that you are connected to the internet."+
-				"<br/>The error was '%s'", err),
+	poolIDs := append([]string{newConfig.Pool}, newConfig.Pools...)
+	var pools []miner.PoolChoice
+	for priority, poolID := range poolIDs {
+		poolInfo, err := gui.GetPool(poolID)
+		if err != nil {
+			if priority == 0 {
+				_ = gui.sendElectronCommand("fatal_error", ElectronMessage{
+					Data: fmt.Sprintf("Unable to configure miner."+
+						"Please check that you are connected to the internet."+
+						"<br/>The error was '%s'", err),
+				})
+				// Give the UI some time to display the message
+				time.Sleep(time.Second * 15)
+				gui.logger.Fatalf("Unable to configure miner: '%s'", err)
+			}
+			gui.logger.Warningf("Unable to fetch fallback pool '%s', skipping: %s", poolID, err)
+			continue
+		}
+
+		var poolAddress string
+		if gui.config.HardwareType == 1 {
+			poolAddress = poolInfo.MiningPorts.Cpu // CPU mining
+		} else if gui.config.HardwareType == 2 {
+			poolAddress = poolInfo.MiningPorts.Gpu // GPU mining
+		} else {
+			poolAddress = poolInfo.Config // if HardwareType failed, use CPU mining
+		}
+		pools = append(pools, miner.PoolChoice{
+			PoolID:   poolID,
+			Address:  poolAddress,
+			Priority: priority,
 		})
-		// Give the UI some time to display the message
-		time.Sleep(time.Second * 15)
-		gui.logger.Fatalf("Unable to configure miner: '%s'", err)
 	}
+	gui.config.Pools = pools
+	gui.poolUnreachableSince = time.Time{}
+	gui.poolActiveBaseline = gui.recorder.Totals()
 
 	// Write the config for the specified miner
 	gui.logger.Debug("Writing miner config")
-
-	var poolAddress string
-	if gui.config.HardwareType == 1 {
-		poolAddress = poolInfo.MiningPorts.Cpu // CPU mining
-	} else if gui.config.HardwareType == 2 {
-		poolAddress = poolInfo.MiningPorts.Gpu // GPU mining
-	} else {
-		poolAddress = poolInfo.Config // if HardwareType failed, use CPU mining
-	}
 	err = gui.miner.WriteConfig(
-		poolAddress,
+		gui.config.Pools,
 		gui.config.Address,
 		gui.config.CoinAlgo,
 		gui.config.XmrigAlgo,
@@ -581,6 +860,20 @@ func (gui *GUI) configureMiner(command bootstrap.MessageIn) {
 	gui.logger.WithFields(logrus.Fields{
 		"type": minerType,
 	}).Info("Miner configured")
+
+	if wasRunning {
+		gui.startMiner()
+	}
+}
+
+// minerPid returns the OS process ID of the currently running miner
+// backend, or 0 if none is configured or running. Used to exclude the
+// miner's own CPU consumption from the scheduler's foreign-CPU-usage signal
+func (gui *GUI) minerPid() int {
+	if gui.miner == nil {
+		return 0
+	}
+	return gui.miner.Pid()
 }
 
 // startMiner starts the miner
@@ -620,15 +913,18 @@ func (gui *GUI) stopMiner() error {
 
 // updateNetworkStats is a single stat update for network and payment info
 func (gui *GUI) updateNetworkStats() {
+	hashrate := gui.recorder.Latest()
 	gui.logger.WithField(
-		"hashrate", gui.lastHashrate,
+		"hashrate", hashrate,
 	).Debug("Fetching network stats")
 	// On firstrun we won't have a config yet
-	if gui.config == nil {
+	if gui.config == nil || len(gui.config.Pools) == 0 {
 		gui.logger.Warning("No config set yet")
 		return
 	}
-	stats, err := gui.GetStats(gui.config.PoolID, gui.lastHashrate, gui.config.Mid)
+	pingStart := time.Now()
+	stats, err := gui.GetStats(gui.config.Pools[0].PoolID, hashrate, gui.config.Mid)
+	gui.metrics.SetPoolPing(float64(time.Since(pingStart).Milliseconds()))
 	if err != nil {
 		gui.logger.Warningf("Unable to get network stats: %s", err)
 	} else {
@@ -636,13 +932,13 @@ func (gui *GUI) updateNetworkStats() {
 		if err != nil {
 			gui.logger.Errorf("Unable to send stats to front-end: %s", err)
 		}
+		gui.rpc.publish("network_stats", stats)
 	}
 }
 
 // updateMiningStats retrieves the miner's stats and updates
 // the front-end
 func (gui *GUI) updateMiningStatsLoop() {
-	lastGraphUpdate := time.Now()
 	for _ = range gui.miningStatsTicker.C {
 		if gui.miner == nil {
 			// No miner set up yet.. wait more
@@ -651,28 +947,56 @@ func (gui *GUI) updateMiningStatsLoop() {
 		}
 		gui.logger.Debug("Fetching mining stats")
 		stats, err := gui.miner.GetStats()
+		gui.scheduler.SetConnected(err == nil)
 		if err != nil {
 			gui.logger.Debugf("Unable to get mining stats, miner not available yet?: %s", err)
 		} else {
-			if gui.lastHashrate == 0 && stats.Hashrate > 0 {
-				gui.lastHashrate = stats.Hashrate
+			firstHashrate := gui.recorder.Latest() == 0 && stats.Hashrate > 0
+			gui.recorder.Sample(stats.Hashrate, stats.ThreadHashrates, stats.SharesAccepted, stats.SharesRejected)
+			totals := gui.recorder.Totals()
+			gui.metrics.SetMiningStats(
+				stats.Hashrate, totals.SharesAccepted, totals.SharesRejected,
+				len(stats.ThreadHashrates), gui.config.CoinAlgo)
+			if firstHashrate {
 				// The first time we get a hashrate, update the BLOC amount so that the
 				// user doesn't think it doesn't work
 				gui.updateNetworkStats()
 			}
-			gui.lastHashrate = stats.Hashrate
 			stats.Address = gui.config.Address
 
-			if time.Since(lastGraphUpdate).Minutes() >= 1 {
-				lastGraphUpdate = time.Now()
-				stats.UpdateGraph = true
-			}
 			statBytes, _ := json.Marshal(&stats)
 			err = bootstrap.SendMessage(gui.window, "miner_stats", string(statBytes))
 			if err != nil {
 				gui.logger.Errorf("Unable to send miner stats to front-end: %s", err)
 			}
+			gui.rpc.publish("miner_stats", stats)
+		}
+	}
+}
+
+// logStatsSummaryLoop logs a compact rolling stats summary every 20 seconds
+// and pushes the same structure to the front-end as miner_stats_summary, so
+// the UI can draw a proper graph from real rolling averages
+func (gui *GUI) logStatsSummaryLoop() {
+	for range gui.summaryTicker.C {
+		if gui.miner == nil {
+			continue
+		}
+		summary := gui.recorder.Summary()
+		gui.logger.Infof(
+			"hashrate_1m=%.2f hashrate_10m=%.2f hashrate_1h=%.2f shares_ok=%d shares_bad=%d reject%%=%.2f",
+			summary.Hashrate1m, summary.Hashrate10m, summary.Hashrate1h,
+			summary.SharesAccepted, summary.SharesRejected, summary.RejectPercent)
+
+		summaryBytes, err := json.Marshal(&summary)
+		if err != nil {
+			gui.logger.Errorf("Unable to marshal stats summary: %s", err)
+			continue
+		}
+		if err := bootstrap.SendMessage(gui.window, "miner_stats_summary", string(summaryBytes)); err != nil {
+			gui.logger.Errorf("Unable to send stats summary to front-end: %s", err)
 		}
+		gui.rpc.publish("miner_stats_summary", summary)
 	}
 }
 
@@ -0,0 +1,27 @@
+package gui
+
+import (
+	"testing"
+
+	gstats "github.com/furiousteam/BLOC-GUI-Miner/src/gui/stats"
+)
+
+func TestRejectPercentSinceActive(t *testing.T) {
+	g := &GUI{recorder: gstats.NewRecorder(gstats.Totals{})}
+	g.recorder.Sample(100, nil, 80, 20)
+
+	// Baseline matches the totals recorded when the current pool became
+	// active; only shares accumulated since then should count
+	g.poolActiveBaseline = gstats.Totals{SharesAccepted: 80, SharesRejected: 10}
+
+	if got := g.rejectPercentSinceActive(); got != 100 {
+		t.Errorf("rejectPercentSinceActive() = %v, want 100 (10 rejects since baseline, 0 accepts)", got)
+	}
+}
+
+func TestRejectPercentSinceActiveNoSharesYet(t *testing.T) {
+	g := &GUI{recorder: gstats.NewRecorder(gstats.Totals{})}
+	if got := g.rejectPercentSinceActive(); got != 0 {
+		t.Errorf("rejectPercentSinceActive() with no shares since baseline = %v, want 0", got)
+	}
+}
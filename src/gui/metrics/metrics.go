@@ -0,0 +1,128 @@
+// Package metrics exposes the miner's current state as Prometheus gauges and
+// counters, so operators running the GUI headlessly on rigs can plug into a
+// Grafana dashboard without scraping the front-end
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// State mirrors the coarse running state of the miner
+type State int
+
+// The values bloc_miner_state reports
+const (
+	StateStopped State = 0
+	StateRunning State = 1
+	StatePaused  State = 2
+)
+
+// Exporter serves the current miner metrics in the Prometheus text
+// exposition format. It is safe for concurrent use
+type Exporter struct {
+	mu sync.Mutex
+
+	algo                string
+	hashrateHS          float64
+	sharesAcceptedTotal uint64
+	sharesRejectedTotal uint64
+	state               State
+	poolPingMS          float64
+	threads             int
+
+	listener net.Listener
+}
+
+// NewExporter creates an idle Exporter. Call Start to begin serving
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// Start binds the exporter's HTTP server to listen and begins serving
+// /metrics. It is a no-op if listen is empty
+func (e *Exporter) Start(listen string) error {
+	if listen == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("unable to bind metrics listener on '%s': %s", listen, err)
+	}
+	e.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+	go http.Serve(listener, mux)
+	return nil
+}
+
+// Stop closes the exporter's listener, if any
+func (e *Exporter) Stop() {
+	if e == nil || e.listener == nil {
+		return
+	}
+	_ = e.listener.Close()
+}
+
+// SetMiningStats updates the hashrate/shares/threads/algo gauges, called
+// from updateMiningStatsLoop
+func (e *Exporter) SetMiningStats(hashrateHS float64, sharesAcceptedTotal, sharesRejectedTotal uint64, threads int, algo string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.hashrateHS = hashrateHS
+	e.sharesAcceptedTotal = sharesAcceptedTotal
+	e.sharesRejectedTotal = sharesRejectedTotal
+	e.threads = threads
+	e.algo = algo
+}
+
+// SetState updates the bloc_miner_state gauge, called whenever the miner's
+// running state changes
+func (e *Exporter) SetState(state State) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = state
+}
+
+// SetPoolPing updates the bloc_miner_pool_ping_ms gauge, called from
+// updateNetworkStats
+func (e *Exporter) SetPoolPing(pingMS float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.poolPingMS = pingMS
+}
+
+func (e *Exporter) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(e.render()))
+}
+
+func (e *Exporter) render() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	algoLabel := fmt.Sprintf(`{algo="%s"}`, escapeLabel(e.algo))
+
+	var b strings.Builder
+	writeMetric(&b, "bloc_miner_hashrate_hs", "gauge", "Current miner hashrate in hashes/second", algoLabel, e.hashrateHS)
+	writeMetric(&b, "bloc_miner_shares_accepted_total", "counter", "Lifetime accepted shares", algoLabel, float64(e.sharesAcceptedTotal))
+	writeMetric(&b, "bloc_miner_shares_rejected_total", "counter", "Lifetime rejected shares", algoLabel, float64(e.sharesRejectedTotal))
+	writeMetric(&b, "bloc_miner_state", "gauge", "0=stopped, 1=running, 2=paused", "", float64(e.state))
+	writeMetric(&b, "bloc_miner_pool_ping_ms", "gauge", "Latency of the last pool API call in milliseconds", "", e.poolPingMS)
+	writeMetric(&b, "bloc_miner_threads", "gauge", "Number of miner threads configured", algoLabel, float64(e.threads))
+	return b.String()
+}
+
+func writeMetric(b *strings.Builder, name, metricType, help, labels string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(b, "%s%s %v\n", name, labels, value)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderIncludesCurrentStats(t *testing.T) {
+	e := NewExporter()
+	e.SetMiningStats(1234.5, 90, 10, 4, "rx")
+	e.SetState(StateRunning)
+	e.SetPoolPing(42)
+
+	out := e.render()
+
+	for _, want := range []string{
+		`bloc_miner_hashrate_hs{algo="rx"} 1234.5`,
+		`bloc_miner_shares_accepted_total{algo="rx"} 90`,
+		`bloc_miner_shares_rejected_total{algo="rx"} 10`,
+		`bloc_miner_state 1`,
+		`bloc_miner_pool_ping_ms 42`,
+		`bloc_miner_threads{algo="rx"} 4`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel(`cn/r "variant"`); got != `cn/r \"variant\"` {
+		t.Errorf(`escapeLabel(cn/r "variant") = %q, want cn/r \"variant\"`, got)
+	}
+}
+
+func TestStartNoopWhenListenEmpty(t *testing.T) {
+	e := NewExporter()
+	if err := e.Start(""); err != nil {
+		t.Errorf("Start(\"\") returned an error, want a no-op: %s", err)
+	}
+	// Stop must tolerate never having been started
+	e.Stop()
+}
@@ -0,0 +1,108 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+func init() {
+	Register("teamredminer", func(path string) Backend {
+		return &execBackend{
+			name:       "teamredminer",
+			execPath:   path,
+			configPath: "teamredminer.conf",
+			apiURL:     "http://127.0.0.1:16002/summary",
+			capabilities: Capabilities{
+				Hardware: []Capability{CapabilityGPUAMD},
+				Algos:    []string{"cn", "cn-lite", "cn/r"},
+			},
+			writeConfigFile: writeFlagStyleConfig,
+			parseStats:      parseTeamRedMinerStats,
+		}
+	})
+	Register("srbminer", func(path string) Backend {
+		return &execBackend{
+			name:       "srbminer",
+			execPath:   path,
+			configPath: "srbminer.conf",
+			apiURL:     "http://127.0.0.1:16003/stats",
+			capabilities: Capabilities{
+				Hardware: []Capability{CapabilityCPU, CapabilityGPUAMD},
+				Algos:    []string{"cn", "cn-lite", "cn/r", "rx"},
+			},
+			writeConfigFile: writeFlagStyleConfig,
+			parseStats:      parseSRBMinerStats,
+		}
+	})
+}
+
+// teamredminer and srbminer are both configured through a flat list of
+// command-line-style flags rather than a structured config file, and like
+// xmr-stak-rx neither supports a pools array; only the highest-priority pool
+// is written, and Backend.Failover rewrites it to reconnect elsewhere
+func writeFlagStyleConfig(
+	path string,
+	pools []PoolChoice,
+	address, coinAlgo, xmrigAlgo, xmrigVariant string,
+	processing ProcessingConfig) error {
+	if len(pools) == 0 {
+		return fmt.Errorf("no pools configured")
+	}
+	pool := pools[0]
+	user := address
+	if pool.User != "" {
+		user = pool.User
+	}
+	cfg := fmt.Sprintf(
+		"-o %s\n-u %s\n-a %s\n--api-listen=127.0.0.1\n",
+		pool.Address, user, coinAlgo)
+	return ioutil.WriteFile(path, []byte(cfg), 0644)
+}
+
+// parseTeamRedMinerStats reads teamredminer's cgminer-derived /summary API, a
+// STATUS/SUMMARY envelope rather than xmrig's hashrate/results shape
+func parseTeamRedMinerStats(body []byte) (Stats, error) {
+	var resp struct {
+		Summary []struct {
+			KHSav    float64 `json:"KHS av"`
+			Accepted int     `json:"Accepted"`
+			Rejected int     `json:"Rejected"`
+		} `json:"SUMMARY"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Stats{}, fmt.Errorf("unable to parse teamredminer summary: %s", err)
+	}
+	if len(resp.Summary) == 0 {
+		return Stats{}, fmt.Errorf("teamredminer summary missing SUMMARY entry")
+	}
+	summary := resp.Summary[0]
+	return Stats{
+		Hashrate:       summary.KHSav * 1000,
+		SharesAccepted: summary.Accepted,
+		SharesRejected: summary.Rejected,
+	}, nil
+}
+
+// parseSRBMinerStats reads SRBMiner-Multi's /stats API, which reports
+// hashrate in H/s already and shares as a nested object rather than xmrig's
+// shares_good/shares_total pair
+func parseSRBMinerStats(body []byte) (Stats, error) {
+	var resp struct {
+		Hashrate struct {
+			Total float64 `json:"total"`
+		} `json:"hashrate"`
+		Shares struct {
+			Accepted int `json:"accepted"`
+			Rejected int `json:"rejected"`
+		} `json:"shares"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Stats{}, fmt.Errorf("unable to parse srbminer stats: %s", err)
+	}
+	return Stats{
+		Hashrate:       resp.Hashrate.Total,
+		SharesAccepted: resp.Shares.Accepted,
+		SharesRejected: resp.Shares.Rejected,
+	}, nil
+}
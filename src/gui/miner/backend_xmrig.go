@@ -0,0 +1,136 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+func init() {
+	Register("xmrig", func(path string) Backend {
+		return newXmrigBackend("xmrig", path, Capabilities{
+			Hardware: []Capability{CapabilityCPU},
+			Algos:    []string{"cn", "cn-lite", "cn/r", "rx"},
+		})
+	})
+	Register("xmrig-cuda", func(path string) Backend {
+		return newXmrigBackend("xmrig-cuda", path, Capabilities{
+			Hardware: []Capability{CapabilityGPUNvidia},
+			Algos:    []string{"cn", "cn-lite", "cn/r", "rx"},
+		})
+	})
+	Register("xmrig-nvidia", func(path string) Backend {
+		return newXmrigBackend("xmrig-nvidia", path, Capabilities{
+			Hardware: []Capability{CapabilityGPUNvidia},
+			Algos:    []string{"cn", "cn-lite", "cn/r"},
+		})
+	})
+	Register("xmrig-proxy", func(path string) Backend {
+		return newXmrigBackend("xmrig-proxy", path, Capabilities{
+			Hardware: []Capability{CapabilityCPU, CapabilityGPUNvidia, CapabilityGPUAMD},
+			Algos:    []string{"cn", "cn-lite", "cn/r", "rx"},
+		})
+	})
+}
+
+// newXmrigBackend builds an execBackend for any member of the xmrig family.
+// They all share the same JSON config shape and HTTP summary API, and only
+// differ in binary name and the hardware/algos they're built for
+func newXmrigBackend(name, path string, capabilities Capabilities) Backend {
+	configPath := name + ".json"
+	return &execBackend{
+		name:            name,
+		execPath:        path,
+		configPath:      configPath,
+		apiURL:          "http://127.0.0.1:16000/1/summary",
+		capabilities:    capabilities,
+		writeConfigFile: writeXmrigConfig,
+		parseStats:      parseXmrigStats,
+	}
+}
+
+// xmrigConfig is a reduced version of xmrig's config.json, covering just the
+// fields the GUI needs to set
+type xmrigConfig struct {
+	APIPort int `json:"api-port"`
+	Pools   []struct {
+		URL     string `json:"url"`
+		User    string `json:"user"`
+		Algo    string `json:"algo"`
+		Variant string `json:"variant"`
+	} `json:"pools"`
+	CPU struct {
+		Enabled  bool `json:"enabled"`
+		Threads  int  `json:"threads,omitempty"`
+		MaxUsage int  `json:"max-cpu-usage,omitempty"`
+	} `json:"cpu"`
+}
+
+func writeXmrigConfig(
+	path string,
+	pools []PoolChoice,
+	address, coinAlgo, xmrigAlgo, xmrigVariant string,
+	processing ProcessingConfig) error {
+	if len(pools) == 0 {
+		return fmt.Errorf("no pools configured")
+	}
+	cfg := xmrigConfig{APIPort: 16000}
+	for _, pool := range pools {
+		user := address
+		if pool.User != "" {
+			user = pool.User
+		}
+		cfg.Pools = append(cfg.Pools, struct {
+			URL     string `json:"url"`
+			User    string `json:"user"`
+			Algo    string `json:"algo"`
+			Variant string `json:"variant"`
+		}{
+			URL:     pool.Address,
+			User:    user,
+			Algo:    xmrigAlgo,
+			Variant: xmrigVariant,
+		})
+	}
+	cfg.CPU.Enabled = true
+	cfg.CPU.Threads = processing.Threads
+	cfg.CPU.MaxUsage = processing.MaxUsage
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to render xmrig config: %s", err)
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func parseXmrigStats(body []byte) (Stats, error) {
+	var summary struct {
+		Hashrate struct {
+			Total   []float64   `json:"total"`
+			Threads [][]float64 `json:"threads"`
+		} `json:"hashrate"`
+		Results struct {
+			SharesGood  int `json:"shares_good"`
+			SharesTotal int `json:"shares_total"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return Stats{}, fmt.Errorf("unable to parse xmrig summary: %s", err)
+	}
+	var hashrate float64
+	if len(summary.Hashrate.Total) > 0 {
+		hashrate = summary.Hashrate.Total[0]
+	}
+	threadHashrates := make([]float64, 0, len(summary.Hashrate.Threads))
+	for _, thread := range summary.Hashrate.Threads {
+		if len(thread) > 0 {
+			threadHashrates = append(threadHashrates, thread[0])
+		}
+	}
+	return Stats{
+		Hashrate:        hashrate,
+		ThreadHashrates: threadHashrates,
+		SharesAccepted:  summary.Results.SharesGood,
+		SharesRejected:  summary.Results.SharesTotal - summary.Results.SharesGood,
+	}, nil
+}
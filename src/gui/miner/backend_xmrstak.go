@@ -0,0 +1,69 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+func init() {
+	Register("xmr-stak-rx", func(path string) Backend {
+		return &execBackend{
+			name:       "xmr-stak-rx",
+			execPath:   path,
+			configPath: "config.txt",
+			apiURL:     "http://127.0.0.1:16001/api.json",
+			capabilities: Capabilities{
+				Hardware: []Capability{CapabilityCPU},
+				Algos:    []string{"rx", "cn/r"},
+			},
+			writeConfigFile: writeXmrStakConfig,
+			parseStats:      parseXmrStakStats,
+		}
+	})
+}
+
+// xmr-stak-rx does not support a pools array the way xmrig does; failover to
+// the next pool is emulated by rewriting this single pool entry with
+// Backend.Failover, which relies on xmr-stak-rx's own reconnect loop to pick
+// up the change rather than restarting the process
+func writeXmrStakConfig(
+	path string,
+	pools []PoolChoice,
+	address, coinAlgo, xmrigAlgo, xmrigVariant string,
+	processing ProcessingConfig) error {
+	if len(pools) == 0 {
+		return fmt.Errorf("no pools configured")
+	}
+	pool := pools[0]
+	user := address
+	if pool.User != "" {
+		user = pool.User
+	}
+	cfg := fmt.Sprintf(`pool_list :
+[
+  { "pool_address" : "%s", "wallet_address" : "%s", "pool_password" : "x",
+    "use_nicehash" : false, "use_tls" : false, "tls_fingerprint" : "",
+    "pool_weight" : 1 },
+],
+currency : "%s",
+httpd_port : 16001,
+`, pool.Address, user, coinAlgo)
+	return ioutil.WriteFile(path, []byte(cfg), 0644)
+}
+
+func parseXmrStakStats(body []byte) (Stats, error) {
+	var summary struct {
+		Hashrate struct {
+			Total []float64 `json:"total"`
+		} `json:"hashrate"`
+	}
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return Stats{}, fmt.Errorf("unable to parse xmr-stak-rx api.json: %s", err)
+	}
+	var hashrate float64
+	if len(summary.Hashrate.Total) > 0 {
+		hashrate = summary.Hashrate.Total[0]
+	}
+	return Stats{Hashrate: hashrate}, nil
+}
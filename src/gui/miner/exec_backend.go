@@ -0,0 +1,157 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// execBackend is the shared implementation for every backend that is driven
+// as a subprocess configured via a JSON file and polled over a local HTTP
+// API, which covers the entire xmrig family (xmrig, xmrig-cuda,
+// xmrig-nvidia, xmrig-proxy) as well as xmr-stak-rx, teamredminer and
+// srbminer. Backend-specific differences (binary name, config shape, API
+// shape, capabilities) are supplied by the embedding type
+type execBackend struct {
+	name         string
+	execPath     string
+	configPath   string
+	apiURL       string
+	capabilities Capabilities
+
+	// writeConfigFile renders this backend's native config format. pools is
+	// an ordered, priority-sorted list; backends with a native pools array
+	// (xmrig) write all of them, single-pool backends write just pools[0]
+	writeConfigFile func(path string, pools []PoolChoice, address, coinAlgo, xmrigAlgo, xmrigVariant string, processing ProcessingConfig) error
+	// parseStats extracts a Stats from this backend's API response body
+	parseStats func(body []byte) (Stats, error)
+
+	mu         sync.Mutex
+	cmd        *exec.Cmd
+	processing ProcessingConfig
+
+	// pools, address, coinAlgo, xmrigAlgo and xmrigVariant are the last
+	// values passed to WriteConfig, kept around so Failover can rewrite the
+	// config file with a new primary pool without the caller having to
+	// resupply everything
+	pools        []PoolChoice
+	address      string
+	coinAlgo     string
+	xmrigAlgo    string
+	xmrigVariant string
+}
+
+func (b *execBackend) GetName() string {
+	return b.name
+}
+
+func (b *execBackend) Capabilities() Capabilities {
+	return b.capabilities
+}
+
+// Detect looks for the backend's executable directly under scanPath
+func (b *execBackend) Detect(scanPath string) (bool, string) {
+	candidate := filepath.Join(scanPath, b.name)
+	if runtimeIsWindows() {
+		candidate += ".exe"
+	}
+	if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+		return true, candidate
+	}
+	return false, ""
+}
+
+func (b *execBackend) WriteConfig(
+	pools []PoolChoice,
+	address string,
+	coinAlgo string,
+	xmrigAlgo string,
+	xmrigVariant string,
+	processing ProcessingConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pools = pools
+	b.address = address
+	b.coinAlgo = coinAlgo
+	b.xmrigAlgo = xmrigAlgo
+	b.xmrigVariant = xmrigVariant
+	b.processing = processing
+	return b.writeConfigFile(b.configPath, pools, address, coinAlgo, xmrigAlgo, xmrigVariant, processing)
+}
+
+// Failover moves next to the front of the pool list and rewrites the config
+// file so the backend's own reconnect logic picks it up. Backends with a
+// native pools array (xmrig) already hold every pool and fail over on their
+// own once it's written; single-pool backends only ever have pools[0] live,
+// so this is what actually makes them reconnect elsewhere
+func (b *execBackend) Failover(next PoolChoice) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pools = ReorderPools(b.pools, next)
+	return b.writeConfigFile(b.configPath, b.pools, b.address, b.coinAlgo, b.xmrigAlgo, b.xmrigVariant, b.processing)
+}
+
+func (b *execBackend) Start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.execPath == "" {
+		return fmt.Errorf("'%s' executable was not found", b.name)
+	}
+	b.cmd = exec.Command(b.execPath, "--config", b.configPath)
+	return b.cmd.Start()
+}
+
+func (b *execBackend) Stop() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cmd == nil || b.cmd.Process == nil {
+		return nil
+	}
+	err := b.cmd.Process.Kill()
+	// Reap the process asynchronously so a killed backend doesn't linger as
+	// a zombie; reconcile/failover/reconfigure can stop and restart the
+	// backend many times over a session
+	go b.cmd.Wait()
+	b.cmd = nil
+	return err
+}
+
+// Pid returns the subprocess's OS process ID, or 0 if it isn't running
+func (b *execBackend) Pid() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cmd == nil || b.cmd.Process == nil {
+		return 0
+	}
+	return b.cmd.Process.Pid
+}
+
+func (b *execBackend) GetProcessingConfig() ProcessingConfig {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.processing
+}
+
+func (b *execBackend) GetStats() (Stats, error) {
+	client := http.Client{Timeout: time.Second * 5}
+	resp, err := client.Get(b.apiURL)
+	if err != nil {
+		return Stats{}, fmt.Errorf("unable to reach '%s' API: %s", b.name, err)
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Stats{}, fmt.Errorf("unable to parse '%s' API response: %s", b.name, err)
+	}
+	return b.parseStats(body)
+}
+
+func runtimeIsWindows() bool {
+	return os.PathSeparator == '\\'
+}
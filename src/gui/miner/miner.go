@@ -0,0 +1,259 @@
+// Package miner abstracts over the bundled and third-party mining backends
+// the GUI can drive. New backends register themselves in the package-level
+// registry via Register, so the rest of the codebase survives backend churn
+// the same way go-ethereum's miner package was decoupled into a configurable
+// subsystem
+package miner
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Config is the persisted configuration for the miner backend in use
+type Config struct {
+	// Type is the registered backend name, e.g. "xmrig" or "xmr-stak-rx"
+	Type string
+	// Path is the backend's executable path. When it names a file, it is used
+	// directly instead of being auto-detected; when it names a directory (or
+	// is empty), that directory (or the bundled "miner" directory) is scanned
+	// for a compatible executable instead
+	Path string
+	// HardwareType is copied from the GUI config: 1 for CPU, 2 for GPU
+	HardwareType int
+}
+
+// ProcessingConfig describes how much of the host the backend may use
+type ProcessingConfig struct {
+	Threads  int
+	MaxUsage int
+}
+
+// Stats is a single sample of what the backend is currently doing
+type Stats struct {
+	Hashrate    float64 `json:"hashrate"`
+	Address     string  `json:"address"`
+	UpdateGraph bool    `json:"updateGraph"`
+	// ThreadHashrates is the per-thread breakdown behind Hashrate, when the
+	// backend reports one
+	ThreadHashrates []float64 `json:"threadHashrates,omitempty"`
+	// SharesAccepted/SharesRejected are cumulative counters since the
+	// backend process started
+	SharesAccepted int `json:"sharesAccepted"`
+	SharesRejected int `json:"sharesRejected"`
+}
+
+// PoolChoice is one entry in an ordered, priority-sorted pool list a backend
+// can fail over between without stopping the mining process
+type PoolChoice struct {
+	// PoolID identifies the pool in the miner API, e.g. for GetPool
+	PoolID string
+	// Address is the hardware-appropriate host:port resolved for this pool
+	Address string
+	// Priority orders the list; lower values are tried first
+	Priority int
+	// User and Pass optionally override the wallet address / worker
+	// password for this specific pool
+	User string
+	Pass string
+}
+
+// ReorderPools returns a copy of pools with next moved to the front,
+// preserving the relative order of the rest. Backend.Failover implementations
+// use it to keep their own pool list in sync with the PoolID they just
+// switched to, and callers use it to keep Config.Pools in sync in turn
+func ReorderPools(pools []PoolChoice, next PoolChoice) []PoolChoice {
+	reordered := make([]PoolChoice, 0, len(pools))
+	reordered = append(reordered, next)
+	for _, p := range pools {
+		if p.PoolID != next.PoolID {
+			reordered = append(reordered, p)
+		}
+	}
+	return reordered
+}
+
+// Capability names a piece of hardware or algorithm a Backend supports
+type Capability string
+
+// The hardware capabilities a Backend can report
+const (
+	CapabilityCPU       Capability = "CPU"
+	CapabilityGPUNvidia Capability = "GPU-NVIDIA"
+	CapabilityGPUAMD    Capability = "GPU-AMD"
+)
+
+// Capabilities describes what hardware and algorithms a Backend supports
+type Capabilities struct {
+	Hardware []Capability
+	Algos    []string
+}
+
+// Supports reports whether a Backend advertises the given hardware capability
+func (c Capabilities) Supports(hw Capability) bool {
+	for _, h := range c.Hardware {
+		if h == hw {
+			return true
+		}
+	}
+	return false
+}
+
+// Miner is the control surface the GUI drives, regardless of which backend
+// is actually configured
+type Miner interface {
+	// GetName returns the human-readable name of the backend, for logging
+	GetName() string
+	// WriteConfig writes the backend-native configuration file. pools is an
+	// ordered, priority-sorted list; backends that support a native pools
+	// array (xmrig) write all of them, backends that only hold one pool at a
+	// time write just the highest-priority entry
+	WriteConfig(
+		pools []PoolChoice,
+		address string,
+		coinAlgo string,
+		xmrigAlgo string,
+		xmrigVariant string,
+		processing ProcessingConfig) error
+	// Start launches the backend process
+	Start() error
+	// Stop terminates the backend process
+	Stop() error
+	// GetStats polls the backend's API for its current hashrate
+	GetStats() (Stats, error)
+	// GetProcessingConfig returns the processing config last written
+	GetProcessingConfig() ProcessingConfig
+	// Failover hot-swaps the active pool to next without stopping the
+	// process. Backends with a native pools array (xmrig) treat this as a
+	// bookkeeping update since the backend already fails over on its own;
+	// single-pool backends (xmr-stak-rx, teamredminer, srbminer) rewrite
+	// their config so the backend's own reconnect logic picks up next
+	Failover(next PoolChoice) error
+	// Pid returns the backend subprocess's OS process ID, or 0 if it isn't
+	// currently running. Used to exclude the miner's own CPU consumption
+	// from the scheduler's foreign-CPU-usage signal
+	Pid() int
+}
+
+// Backend is a Miner that can also be auto-detected and report what
+// hardware and algorithms it supports. Third-party backends (xmrig-cuda,
+// xmrig-nvidia, xmr-stak-rx, teamredminer, srbminer, xmrig-proxy, ...)
+// implement this to be picked up by CreateMiner / DetermineMinerType
+type Backend interface {
+	Miner
+	// Detect reports whether this backend's executable is present under
+	// scanPath, and if so, its full path
+	Detect(scanPath string) (bool, string)
+	// Capabilities reports what hardware and algorithms this backend supports
+	Capabilities() Capabilities
+}
+
+// factory builds a Backend bound to the given executable path. path may be
+// empty when the factory is only used for Detect/Capabilities
+type factory func(path string) Backend
+
+// registry holds every backend registered via Register, in registration
+// order so DetermineMinerType has a stable, predictable scan order
+var registry = struct {
+	names  []string
+	byName map[string]factory
+}{byName: make(map[string]factory)}
+
+// Register adds a backend to the registry under name. It is meant to be
+// called from each backend's init() function
+func Register(name string, newBackend factory) {
+	if _, exists := registry.byName[name]; !exists {
+		registry.names = append(registry.names, name)
+	}
+	registry.byName[name] = newBackend
+}
+
+// CreateMiner builds the Miner described by cfg. cfg.Type must name a
+// registered backend; cfg.Path, when set, overrides auto-detection and is
+// used as-is
+func CreateMiner(cfg Config) (Miner, error) {
+	newBackend, ok := registry.byName[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a registered miner backend", cfg.Type)
+	}
+	return newBackend(cfg.Path), nil
+}
+
+// DetermineMinerType scans scanPath for a known backend executable, trying
+// every registered backend in registration order, and returns its name and
+// resolved executable path
+func DetermineMinerType(scanPath string) (string, string, error) {
+	for _, name := range registry.names {
+		backend := registry.byName[name]("")
+		if found, execPath := backend.Detect(scanPath); found {
+			return name, execPath, nil
+		}
+	}
+	return "", "", fmt.Errorf("no supported miner executable found in '%s'", scanPath)
+}
+
+// SelectBackend scans scanPath for the first registered backend that both
+// supports the given hardware/algo combination and has an executable
+// present, preserving registration order as the tie-break between backends
+// that are equally compatible
+func SelectBackend(scanPath string, hardware Capability, algo string) (string, string, error) {
+	for _, name := range CompatibleBackends(hardware, algo) {
+		backend := registry.byName[name]("")
+		if found, execPath := backend.Detect(scanPath); found {
+			return name, execPath, nil
+		}
+	}
+	return "", "", fmt.Errorf(
+		"no miner executable for hardware '%s' and algo '%s' found in '%s'", hardware, algo, scanPath)
+}
+
+// SelectBackendForPath picks the registered backend compatible with the
+// given hardware/algo combination and binds it to execPath directly, rather
+// than re-discovering an executable under a scan directory. It's used when
+// the user (or RPC Reconfigure) supplies an exact executable path. Since the
+// path itself carries no type information, it prefers the compatible
+// backend whose conventional name matches the executable's filename (e.g.
+// "xmrig" for ".../xmrig.exe"), falling back to the first compatible
+// backend in registration order when the filename doesn't match any of them
+func SelectBackendForPath(execPath string, hardware Capability, algo string) (string, error) {
+	names := CompatibleBackends(hardware, algo)
+	if len(names) == 0 {
+		return "", fmt.Errorf(
+			"no registered miner backend supports hardware '%s' and algo '%s'", hardware, algo)
+	}
+	base := filepath.Base(execPath)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	for _, name := range names {
+		if name == base {
+			return name, nil
+		}
+	}
+	return names[0], nil
+}
+
+// CompatibleBackends returns the registered backend names able to run the
+// given hardware type and algo, in registration order
+func CompatibleBackends(hardware Capability, algo string) []string {
+	var names []string
+	for _, name := range registry.names {
+		caps := registry.byName[name]("").Capabilities()
+		if !caps.Supports(hardware) {
+			continue
+		}
+		if algo != "" && !containsAlgo(caps.Algos, algo) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func containsAlgo(algos []string, algo string) bool {
+	for _, a := range algos {
+		if a == algo {
+			return true
+		}
+	}
+	return false
+}
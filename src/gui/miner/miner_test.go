@@ -0,0 +1,101 @@
+package miner
+
+import "testing"
+
+func TestReorderPoolsMovesNextToFront(t *testing.T) {
+	pools := []PoolChoice{
+		{PoolID: "a", Priority: 0},
+		{PoolID: "b", Priority: 1},
+		{PoolID: "c", Priority: 2},
+	}
+
+	reordered := ReorderPools(pools, pools[1])
+
+	want := []string{"b", "a", "c"}
+	if len(reordered) != len(want) {
+		t.Fatalf("ReorderPools returned %d pools, want %d", len(reordered), len(want))
+	}
+	for i, id := range want {
+		if reordered[i].PoolID != id {
+			t.Errorf("reordered[%d].PoolID = %q, want %q", i, reordered[i].PoolID, id)
+		}
+	}
+
+	// The original slice must be left untouched
+	if pools[0].PoolID != "a" {
+		t.Error("ReorderPools must not mutate its input slice")
+	}
+}
+
+func TestReorderPoolsUnknownNext(t *testing.T) {
+	pools := []PoolChoice{{PoolID: "a"}, {PoolID: "b"}}
+	next := PoolChoice{PoolID: "c"}
+
+	reordered := ReorderPools(pools, next)
+
+	if len(reordered) != 3 || reordered[0].PoolID != "c" {
+		t.Errorf("ReorderPools(%v, %v) = %v, want next prepended", pools, next, reordered)
+	}
+}
+
+func TestCompatibleBackendsFiltersByHardwareAndAlgo(t *testing.T) {
+	names := CompatibleBackends(CapabilityGPUAMD, "cn")
+	if !containsName(names, "teamredminer") {
+		t.Errorf("CompatibleBackends(GPU-AMD, cn) = %v, want it to include teamredminer", names)
+	}
+	if containsName(names, "xmrig") {
+		t.Errorf("CompatibleBackends(GPU-AMD, cn) = %v, xmrig only supports CPU", names)
+	}
+
+	cpuRx := CompatibleBackends(CapabilityCPU, "rx")
+	if !containsName(cpuRx, "xmrig") || !containsName(cpuRx, "xmr-stak-rx") {
+		t.Errorf("CompatibleBackends(CPU, rx) = %v, want xmrig and xmr-stak-rx", cpuRx)
+	}
+	if containsName(cpuRx, "teamredminer") {
+		t.Errorf("CompatibleBackends(CPU, rx) = %v, teamredminer does not run on CPU", cpuRx)
+	}
+}
+
+func TestSelectBackendNoExecutableFound(t *testing.T) {
+	_, _, err := SelectBackend(t.TempDir(), CapabilityCPU, "rx")
+	if err == nil {
+		t.Fatal("expected an error when no compatible executable is present")
+	}
+}
+
+func TestSelectBackendForPathMatchesFilename(t *testing.T) {
+	// Both srbminer and xmrig support CPU/cn; the filename should resolve
+	// the ambiguity instead of silently picking registration order
+	name, err := SelectBackendForPath("/opt/miner/xmrig.exe", CapabilityCPU, "cn")
+	if err != nil {
+		t.Fatalf("SelectBackendForPath returned an unexpected error: %s", err)
+	}
+	if name != "xmrig" {
+		t.Errorf("SelectBackendForPath(xmrig.exe) = %q, want %q", name, "xmrig")
+	}
+}
+
+func TestSelectBackendForPathFallsBackWhenFilenameUnknown(t *testing.T) {
+	name, err := SelectBackendForPath("/opt/miner/custom-binary", CapabilityGPUAMD, "cn")
+	if err != nil {
+		t.Fatalf("SelectBackendForPath returned an unexpected error: %s", err)
+	}
+	if !containsName(CompatibleBackends(CapabilityGPUAMD, "cn"), name) {
+		t.Errorf("SelectBackendForPath returned %q, not a backend compatible with GPU-AMD/cn", name)
+	}
+}
+
+func TestSelectBackendForPathNoCompatibleBackend(t *testing.T) {
+	if _, err := SelectBackendForPath("/opt/miner/custom-binary", CapabilityGPUNvidia, "unknown-algo"); err == nil {
+		t.Fatal("expected an error when no registered backend supports the hardware/algo combination")
+	}
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
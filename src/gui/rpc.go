@@ -0,0 +1,344 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	bootstrap "github.com/asticode/go-astilectron-bootstrap"
+	"github.com/sirupsen/logrus"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request as sent by bloc-minerctl or any other
+// local controller
+type rpcRequest struct {
+	ID     interface{}     `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response
+type rpcResponse struct {
+	ID     interface{} `json:"id"`
+	Result interface{} `json:"result,omitempty"`
+	Error  *rpcError   `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcHandler answers a single JSON-RPC call
+type rpcHandler func(gui *GUI, params json.RawMessage) (interface{}, error)
+
+// rpcMethods lists every method the control API exposes, mirroring the
+// operations the Electron front-end already drives via handleElectronCommands
+var rpcMethods = map[string]rpcHandler{
+	"Start":               rpcStart,
+	"Stop":                rpcStop,
+	"Reconfigure":         rpcReconfigure,
+	"GetStats":            rpcGetStats,
+	"GetProcessingConfig": rpcGetProcessingConfig,
+	"GetPoolList":         rpcGetPoolList,
+}
+
+// rpcReconfigureParams mirrors the fields configureMiner reads off of the
+// Electron payload
+type rpcReconfigureParams struct {
+	PoolID string `json:"poolID"`
+	// FailoverPoolIDs are additional pool IDs tried, in order, when PoolID
+	// becomes unhealthy. See Config.Failover
+	FailoverPoolIDs []string `json:"failoverPoolIDs"`
+	Address         string   `json:"address"`
+	Threads         int      `json:"threads"`
+	MaxCPU          int      `json:"maxCPU"`
+	HardwareType    int      `json:"hardwareType"`
+	Algo            string   `json:"algo"`
+	// MinerPath optionally points configureMiner at a user-installed miner
+	// binary instead of scanning the bundled "miner" directory
+	MinerPath string `json:"minerPath"`
+}
+
+// rpcServer is the opt-in local control API described by RPCConfig. It
+// exposes the same operations as handleElectronCommands over HTTP+JSON-RPC
+// and a Unix socket / named-pipe transport, plus push subscriptions for
+// miner_stats and network_stats
+type rpcServer struct {
+	gui *GUI
+
+	httpListener net.Listener
+	ipcListener  net.Listener
+
+	subsMu sync.Mutex
+	subs   map[string][]chan interface{}
+
+	logger *logrus.Entry
+}
+
+// startRPCServer brings up the transports enabled in cfg. It is a no-op
+// unless cfg.Enabled is set
+func (gui *GUI) startRPCServer(cfg RPCConfig) (*rpcServer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	srv := &rpcServer{
+		gui:    gui,
+		subs:   make(map[string][]chan interface{}),
+		logger: gui.logger.WithField("component", "rpc"),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc", srv.handleHTTP(cfg))
+	mux.HandleFunc("/subscribe/", srv.handleSubscribe(cfg))
+
+	if cfg.BindAddress != "" {
+		listener, err := net.Listen("tcp", cfg.BindAddress)
+		if err != nil {
+			return nil, fmt.Errorf("unable to bind RPC listener on '%s': %s", cfg.BindAddress, err)
+		}
+		srv.httpListener = listener
+		go func() {
+			if err := http.Serve(listener, mux); err != nil {
+				srv.logger.Debugf("HTTP RPC transport stopped: %s", err)
+			}
+		}()
+		srv.logger.WithField("address", cfg.BindAddress).Info("RPC server listening on HTTP")
+	}
+
+	if cfg.SocketPath != "" {
+		_ = os.Remove(cfg.SocketPath)
+		listener, err := net.Listen("unix", cfg.SocketPath)
+		if err != nil {
+			srv.Stop()
+			return nil, fmt.Errorf("unable to bind RPC socket at '%s': %s", cfg.SocketPath, err)
+		}
+		srv.ipcListener = listener
+		go func() {
+			if err := http.Serve(listener, mux); err != nil {
+				srv.logger.Debugf("IPC RPC transport stopped: %s", err)
+			}
+		}()
+		srv.logger.WithField("path", cfg.SocketPath).Info("RPC server listening on Unix socket")
+	}
+
+	return srv, nil
+}
+
+// Stop closes every transport the RPC server opened
+func (srv *rpcServer) Stop() {
+	if srv == nil {
+		return
+	}
+	if srv.httpListener != nil {
+		_ = srv.httpListener.Close()
+	}
+	if srv.ipcListener != nil {
+		_ = srv.ipcListener.Close()
+	}
+}
+
+// publish pushes data to every subscriber of the given topic
+func (srv *rpcServer) publish(topic string, data interface{}) {
+	if srv == nil {
+		return
+	}
+	srv.subsMu.Lock()
+	defer srv.subsMu.Unlock()
+	for _, ch := range srv.subs[topic] {
+		select {
+		case ch <- data:
+		default:
+			// Slow subscriber, drop the update rather than block the miner
+		}
+	}
+}
+
+func (srv *rpcServer) authorized(cfg RPCConfig, r *http.Request) bool {
+	if cfg.AuthToken == "" {
+		return true
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") == cfg.AuthToken
+}
+
+func (srv *rpcServer) originAllowed(cfg RPCConfig, r *http.Request) bool {
+	if len(cfg.AllowedOrigins) == 0 {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	for _, o := range cfg.AllowedOrigins {
+		if o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (srv *rpcServer) methodAllowed(cfg RPCConfig, method string) bool {
+	if len(cfg.EnabledMethods) == 0 {
+		return true
+	}
+	for _, m := range cfg.EnabledMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (srv *rpcServer) handleHTTP(cfg RPCConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !srv.originAllowed(cfg, r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if !srv.authorized(cfg, r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		resp := rpcResponse{ID: req.ID}
+		handler, ok := rpcMethods[req.Method]
+		if !ok {
+			resp.Error = &rpcError{Code: -32601, Message: fmt.Sprintf("method '%s' not found", req.Method)}
+		} else if !srv.methodAllowed(cfg, req.Method) {
+			resp.Error = &rpcError{Code: -32601, Message: fmt.Sprintf("method '%s' is disabled", req.Method)}
+		} else {
+			result, err := handler(srv.gui, req.Params)
+			if err != nil {
+				resp.Error = &rpcError{Code: -32000, Message: err.Error()}
+			} else {
+				resp.Result = result
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// handleSubscribe serves a push stream of newline-delimited JSON for
+// miner_stats or network_stats, matching the topics the Electron front-end
+// already listens for
+func (srv *rpcServer) handleSubscribe(cfg RPCConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !srv.originAllowed(cfg, r) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		if !srv.authorized(cfg, r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		topic := strings.TrimPrefix(r.URL.Path, "/subscribe/")
+		if topic != "miner_stats" && topic != "network_stats" && topic != "miner_stats_summary" {
+			http.Error(w, fmt.Sprintf("unknown topic '%s'", topic), http.StatusNotFound)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		ch := make(chan interface{}, 8)
+		srv.subsMu.Lock()
+		srv.subs[topic] = append(srv.subs[topic], ch)
+		srv.subsMu.Unlock()
+		defer func() {
+			srv.subsMu.Lock()
+			defer srv.subsMu.Unlock()
+			subs := srv.subs[topic]
+			for i, c := range subs {
+				if c == ch {
+					srv.subs[topic] = append(subs[:i], subs[i+1:]...)
+					break
+				}
+			}
+		}()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case data := <-ch:
+				if err := json.NewEncoder(w).Encode(data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// rpcStart mirrors the Electron start-miner handler: it only clears the
+// user-paused override and lets reconcile drive the actual start, so the
+// scheduler's view of gui.minerState never drifts from the running process
+func rpcStart(gui *GUI, _ json.RawMessage) (interface{}, error) {
+	if gui.miner == nil {
+		return nil, fmt.Errorf("miner is not configured yet")
+	}
+	gui.scheduler.SetUserPaused(false)
+	gui.reconcile()
+	return "Ok", nil
+}
+
+// rpcStop mirrors the Electron stop-miner handler, see rpcStart
+func rpcStop(gui *GUI, _ json.RawMessage) (interface{}, error) {
+	gui.scheduler.SetUserPaused(true)
+	gui.reconcile()
+	return "Ok", nil
+}
+
+func rpcReconfigure(gui *GUI, params json.RawMessage) (interface{}, error) {
+	var p rpcReconfigureParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid Reconfigure params: %s", err)
+	}
+	payload, err := json.Marshal(frontendConfig{
+		Address:      p.Address,
+		Pool:         p.PoolID,
+		Pools:        p.FailoverPoolIDs,
+		CoinAlgo:     p.Algo,
+		HardwareType: p.HardwareType,
+		Threads:      p.Threads,
+		MaxCPU:       p.MaxCPU,
+		MinerPath:    p.MinerPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	gui.configureMiner(bootstrap.MessageIn{Name: "save-configuration", Payload: payload})
+	return "Ok", nil
+}
+
+func rpcGetStats(gui *GUI, _ json.RawMessage) (interface{}, error) {
+	if gui.miner == nil {
+		return nil, fmt.Errorf("miner is not configured yet")
+	}
+	return gui.miner.GetStats()
+}
+
+func rpcGetProcessingConfig(gui *GUI, _ json.RawMessage) (interface{}, error) {
+	if gui.miner == nil {
+		return nil, fmt.Errorf("miner is not configured yet")
+	}
+	return gui.miner.GetProcessingConfig(), nil
+}
+
+func rpcGetPoolList(gui *GUI, _ json.RawMessage) (interface{}, error) {
+	return gui.GetPoolList()
+}
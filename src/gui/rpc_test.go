@@ -0,0 +1,68 @@
+package gui
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRPCServerAuthorized(t *testing.T) {
+	srv := &rpcServer{}
+
+	if !srv.authorized(RPCConfig{}, httptest.NewRequest("POST", "/rpc", nil)) {
+		t.Error("authorized() with no AuthToken configured should allow every request")
+	}
+
+	cfg := RPCConfig{AuthToken: "secret"}
+	if srv.authorized(cfg, httptest.NewRequest("POST", "/rpc", nil)) {
+		t.Error("authorized() should reject a request with no Authorization header")
+	}
+
+	req := httptest.NewRequest("POST", "/rpc", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	if !srv.authorized(cfg, req) {
+		t.Error("authorized() should accept a matching Bearer token")
+	}
+
+	req = httptest.NewRequest("POST", "/rpc", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if srv.authorized(cfg, req) {
+		t.Error("authorized() should reject a mismatched Bearer token")
+	}
+}
+
+func TestRPCServerOriginAllowed(t *testing.T) {
+	srv := &rpcServer{}
+
+	if !srv.originAllowed(RPCConfig{}, httptest.NewRequest("GET", "/subscribe/miner_stats", nil)) {
+		t.Error("originAllowed() with an empty AllowedOrigins list should allow every origin")
+	}
+
+	cfg := RPCConfig{AllowedOrigins: []string{"https://example.com"}}
+	req := httptest.NewRequest("GET", "/subscribe/miner_stats", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	if srv.originAllowed(cfg, req) {
+		t.Error("originAllowed() should reject an origin not in the whitelist")
+	}
+
+	req = httptest.NewRequest("GET", "/subscribe/miner_stats", nil)
+	req.Header.Set("Origin", "https://example.com")
+	if !srv.originAllowed(cfg, req) {
+		t.Error("originAllowed() should accept a whitelisted origin")
+	}
+}
+
+func TestRPCServerMethodAllowed(t *testing.T) {
+	srv := &rpcServer{}
+
+	if !srv.methodAllowed(RPCConfig{}, "Stop") {
+		t.Error("methodAllowed() with an empty EnabledMethods list should allow every method")
+	}
+
+	cfg := RPCConfig{EnabledMethods: []string{"Start", "GetStats"}}
+	if !srv.methodAllowed(cfg, "GetStats") {
+		t.Error("methodAllowed() should allow a whitelisted method")
+	}
+	if srv.methodAllowed(cfg, "Stop") {
+		t.Error("methodAllowed() should reject a method not in the whitelist")
+	}
+}
@@ -0,0 +1,231 @@
+// Package scheduler decides whether the miner should be running, based on a
+// user-configured Schedule plus live system signals (time of day, AC power,
+// foreign CPU usage and network connectivity)
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// State mirrors the MinerState the front-end understands
+type State int
+
+// The states a Scheduler can report, similar to the state model csminer uses
+const (
+	Active State = iota
+	PausedUser
+	PausedTimeExcluded
+	PausedBattery
+	PausedNoConnection
+)
+
+// String renders a State the way it is sent to the front-end
+func (s State) String() string {
+	switch s {
+	case Active:
+		return "MINING_ACTIVE"
+	case PausedUser:
+		return "MINING_PAUSED_USER"
+	case PausedTimeExcluded:
+		return "MINING_PAUSED_TIME_EXCLUDED"
+	case PausedBattery:
+		return "MINING_PAUSED_BATTERY"
+	case PausedNoConnection:
+		return "MINING_PAUSED_NO_CONNECTION"
+	default:
+		return "MINING_PAUSED_TIME_EXCLUDED"
+	}
+}
+
+// Window is a recurring exclusion window, e.g. weekdays 09:00-17:00
+type Window struct {
+	// Days the window applies to. An empty list means every day
+	Days []time.Weekday
+	// Start and End are "HH:MM" in the local timezone
+	Start string
+	End   string
+}
+
+// Schedule is the user-configured part of the scheduler, persisted on Config
+type Schedule struct {
+	// ExcludedWindows are the hours mining must stay paused, e.g. office hours
+	ExcludedWindows []Window
+	// ACPowerOnly pauses mining whenever the machine is running on battery
+	ACPowerOnly bool
+	// CPUIdleThreshold pauses mining when foreground CPU usage from other
+	// processes exceeds this percentage, sustained over CPUIdleWindow. Zero
+	// disables the check
+	CPUIdleThreshold float64
+	CPUIdleWindow    time.Duration
+	// UserPaused is the manual override set via the 'stop mining' button
+	UserPaused bool
+}
+
+// SystemSignals supplies the live system state the Scheduler can't determine
+// on its own. Each field may be left nil, in which case that check is skipped
+type SystemSignals struct {
+	// OnBattery reports whether the machine is currently running on battery
+	OnBattery func() (bool, error)
+	// ForeignCPUUsage reports the CPU usage percentage from processes other
+	// than the miner itself, averaged over the recent window
+	ForeignCPUUsage func() (float64, error)
+}
+
+// Scheduler holds the current Schedule and live signals and decides, on
+// demand, what State the miner should be in
+type Scheduler struct {
+	mu        sync.Mutex
+	schedule  Schedule
+	signals   SystemSignals
+	connected bool
+	now       func() time.Time
+	// cpuOverSince is when ForeignCPUUsage most recently crossed above
+	// CPUIdleThreshold, or the zero Time if it is currently at or below it.
+	// Evaluate only pauses once this has held for CPUIdleWindow
+	cpuOverSince time.Time
+}
+
+// New creates a Scheduler with the given starting schedule. now defaults to
+// time.Now if nil, which is only ever overridden by tests
+func New(schedule Schedule, signals SystemSignals) *Scheduler {
+	return &Scheduler{
+		schedule:  schedule,
+		signals:   signals,
+		connected: true,
+		now:       time.Now,
+	}
+}
+
+// SetSchedule replaces the user-configured schedule, e.g. after 'set-schedule'
+func (s *Scheduler) SetSchedule(schedule Schedule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule = schedule
+}
+
+// Schedule returns a copy of the current schedule, e.g. for 'get-schedule'
+func (s *Scheduler) Schedule() Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.schedule
+}
+
+// SetUserPaused toggles the manual override
+func (s *Scheduler) SetUserPaused(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule.UserPaused = paused
+}
+
+// SetConnected records whether the miner currently has a usable pool
+// connection
+func (s *Scheduler) SetConnected(connected bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connected = connected
+}
+
+// Evaluate returns the State the miner should currently be in. Checks are
+// ordered from the most to the least user-intentional, so the most relevant
+// reason is the one surfaced to the front-end
+func (s *Scheduler) Evaluate() State {
+	s.mu.Lock()
+	schedule := s.schedule
+	connected := s.connected
+	s.mu.Unlock()
+
+	if schedule.UserPaused {
+		return PausedUser
+	}
+	if !connected {
+		return PausedNoConnection
+	}
+	if schedule.ACPowerOnly && s.signals.OnBattery != nil {
+		if onBattery, err := s.signals.OnBattery(); err == nil && onBattery {
+			return PausedBattery
+		}
+	}
+	if inExcludedWindow(schedule.ExcludedWindows, s.now()) {
+		return PausedTimeExcluded
+	}
+	// Sustained foreign CPU usage is grouped under PausedTimeExcluded: both
+	// are automatic, non-manual throttling conditions rather than something
+	// the user explicitly asked for
+	if schedule.CPUIdleThreshold > 0 && s.signals.ForeignCPUUsage != nil {
+		if s.cpuOverThreshold(schedule) {
+			return PausedTimeExcluded
+		}
+	}
+	return Active
+}
+
+// cpuOverThreshold reports whether ForeignCPUUsage has stayed above
+// schedule.CPUIdleThreshold for at least schedule.CPUIdleWindow, tracking
+// when the threshold was first crossed across successive Evaluate calls
+func (s *Scheduler) cpuOverThreshold(schedule Schedule) bool {
+	usage, err := s.signals.ForeignCPUUsage()
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if usage <= schedule.CPUIdleThreshold {
+		s.cpuOverSince = time.Time{}
+		return false
+	}
+	now := s.now()
+	if s.cpuOverSince.IsZero() {
+		s.cpuOverSince = now
+	}
+	return now.Sub(s.cpuOverSince) >= schedule.CPUIdleWindow
+}
+
+func inExcludedWindow(windows []Window, at time.Time) bool {
+	for _, w := range windows {
+		if !dayMatches(w.Days, at.Weekday()) {
+			continue
+		}
+		start, err := time.ParseDuration(hhmmToDuration(w.Start))
+		if err != nil {
+			continue
+		}
+		end, err := time.ParseDuration(hhmmToDuration(w.End))
+		if err != nil {
+			continue
+		}
+		sinceMidnight := time.Duration(at.Hour())*time.Hour +
+			time.Duration(at.Minute())*time.Minute
+		if start > end {
+			// Overnight window, e.g. 22:00-06:00: it matches everything from
+			// Start to midnight plus everything from midnight to End
+			if sinceMidnight >= start || sinceMidnight < end {
+				return true
+			}
+		} else if sinceMidnight >= start && sinceMidnight < end {
+			return true
+		}
+	}
+	return false
+}
+
+func dayMatches(days []time.Weekday, today time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if d == today {
+			return true
+		}
+	}
+	return false
+}
+
+// hhmmToDuration turns "HH:MM" into a string time.ParseDuration accepts
+func hhmmToDuration(hhmm string) string {
+	if len(hhmm) != 5 || hhmm[2] != ':' {
+		return "0s"
+	}
+	return hhmm[0:2] + "h" + hhmm[3:5] + "m"
+}
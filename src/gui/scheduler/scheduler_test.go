@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInExcludedWindowOvernight(t *testing.T) {
+	windows := []Window{{Start: "22:00", End: "06:00"}}
+
+	cases := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before window, evening", time.Date(2026, 7, 30, 21, 59, 0, 0, time.UTC), false},
+		{"just after start", time.Date(2026, 7, 30, 22, 30, 0, 0, time.UTC), true},
+		{"just before midnight", time.Date(2026, 7, 30, 23, 59, 0, 0, time.UTC), true},
+		{"just after midnight", time.Date(2026, 7, 31, 0, 30, 0, 0, time.UTC), true},
+		{"just before end", time.Date(2026, 7, 31, 5, 59, 0, 0, time.UTC), true},
+		{"at end, excluded again", time.Date(2026, 7, 31, 6, 0, 0, 0, time.UTC), false},
+		{"mid-day", time.Date(2026, 7, 31, 12, 0, 0, 0, time.UTC), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := inExcludedWindow(windows, c.at); got != c.want {
+				t.Errorf("inExcludedWindow(%s) = %v, want %v", c.at, got, c.want)
+			}
+		})
+	}
+}
+
+func TestInExcludedWindowSameDay(t *testing.T) {
+	windows := []Window{{Start: "09:00", End: "17:00"}}
+
+	if inExcludedWindow(windows, time.Date(2026, 7, 30, 8, 59, 0, 0, time.UTC)) {
+		t.Error("expected 08:59 to be outside a 09:00-17:00 window")
+	}
+	if !inExcludedWindow(windows, time.Date(2026, 7, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Error("expected 12:00 to be inside a 09:00-17:00 window")
+	}
+	if inExcludedWindow(windows, time.Date(2026, 7, 30, 17, 0, 0, 0, time.UTC)) {
+		t.Error("expected 17:00 to be outside a 09:00-17:00 window")
+	}
+}
+
+func TestDayMatches(t *testing.T) {
+	if !dayMatches(nil, time.Monday) {
+		t.Error("an empty Days list should match every day")
+	}
+	days := []time.Weekday{time.Saturday, time.Sunday}
+	if dayMatches(days, time.Monday) {
+		t.Error("Monday should not match a weekend-only list")
+	}
+	if !dayMatches(days, time.Sunday) {
+		t.Error("Sunday should match a weekend-only list")
+	}
+}
+
+// fakeClock lets Evaluate be driven deterministically across calls
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestEvaluateSustainsCPUIdleOverWindow(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 7, 30, 12, 0, 0, 0, time.UTC)}
+	usage := 0.0
+	sched := New(Schedule{
+		CPUIdleThreshold: 50,
+		CPUIdleWindow:    time.Minute,
+	}, SystemSignals{
+		ForeignCPUUsage: func() (float64, error) { return usage, nil },
+	})
+	sched.now = clock.now
+
+	usage = 80
+	if got := sched.Evaluate(); got != Active {
+		t.Fatalf("Evaluate() on the first over-threshold tick = %v, want Active (not yet sustained)", got)
+	}
+
+	clock.t = clock.t.Add(30 * time.Second)
+	if got := sched.Evaluate(); got != Active {
+		t.Fatalf("Evaluate() before CPUIdleWindow elapsed = %v, want Active", got)
+	}
+
+	clock.t = clock.t.Add(31 * time.Second)
+	if got := sched.Evaluate(); got != PausedTimeExcluded {
+		t.Fatalf("Evaluate() once sustained past CPUIdleWindow = %v, want PausedTimeExcluded", got)
+	}
+
+	usage = 10
+	if got := sched.Evaluate(); got != Active {
+		t.Fatalf("Evaluate() after usage drops back below threshold = %v, want Active", got)
+	}
+}
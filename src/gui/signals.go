@@ -0,0 +1,208 @@
+package gui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// onBatteryPower and foreignCPUUsage feed the scheduler the live system
+// signals it can't determine on its own. They are best-effort: platforms
+// without a cheap way to read this return an error, which the scheduler
+// treats as "skip this check" rather than as a pause
+var errSignalUnsupported = errors.New("signal not supported on this platform")
+
+// onBatteryPower reports whether the machine is currently running on
+// battery power. Linux exposes this cheaply via sysfs; macOS/Windows need
+// IOKit/WMI calls that aren't worth the cgo/syscall surface for a
+// best-effort signal, so they report unsupported there and the scheduler
+// simply skips the ACPowerOnly check
+func onBatteryPower() (bool, error) {
+	if runtime.GOOS != "linux" {
+		return false, errSignalUnsupported
+	}
+
+	const powerSupplyDir = "/sys/class/power_supply"
+	entries, err := ioutil.ReadDir(powerSupplyDir)
+	if err != nil {
+		return false, errSignalUnsupported
+	}
+
+	sawMains := false
+	for _, entry := range entries {
+		typeBytes, err := ioutil.ReadFile(filepath.Join(powerSupplyDir, entry.Name(), "type"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(typeBytes)) != "Mains" {
+			continue
+		}
+		sawMains = true
+		onlineBytes, err := ioutil.ReadFile(filepath.Join(powerSupplyDir, entry.Name(), "online"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(onlineBytes)) == "1" {
+			return false, nil
+		}
+	}
+	if !sawMains {
+		return false, errSignalUnsupported
+	}
+	return true, nil
+}
+
+// cpuSample is a snapshot of /proc/stat's aggregate CPU line, used to derive
+// a usage percentage between two calls
+type cpuSample struct {
+	idle  uint64
+	total uint64
+}
+
+var (
+	cpuSampleMu   sync.Mutex
+	lastCPUSample cpuSample
+	lastCPUAt     time.Time
+
+	// lastMinerSample/lastMinerPid track the miner subprocess's own cumulative
+	// CPU ticks between calls, keyed to the pid they were read from so a
+	// restarted (or newly started/stopped) miner doesn't diff against a stale
+	// process's counters
+	lastMinerSample uint64
+	lastMinerPid    int
+)
+
+// foreignCPUUsage reports the CPU usage percentage consumed by processes
+// other than the miner itself, used to detect when the user is back at
+// their machine and needs the CPU. On Linux this is derived from
+// /proc/stat, with the miner's own ticks (read from /proc/<minerPid>/stat)
+// subtracted out first; otherwise CPU mining would peg "foreign" usage near
+// 100% and trip CPUIdleThreshold the moment mining starts. minerPid is 0
+// when the miner isn't currently running, in which case all busy time is
+// foreign. Other platforms report unsupported and the scheduler skips the
+// CPUIdleThreshold check there. The first call after startup (or after a
+// long gap) has nothing to diff against, so it reports unsupported too
+func foreignCPUUsage(minerPid int) (float64, error) {
+	if runtime.GOOS != "linux" {
+		return 0, errSignalUnsupported
+	}
+
+	sample, err := readAggregateCPUSample()
+	if err != nil {
+		return 0, errSignalUnsupported
+	}
+
+	var minerTicks uint64
+	if minerPid != 0 {
+		minerTicks, err = readProcessCPUTicks(minerPid)
+		if err != nil {
+			// The miner may have exited between the caller reading its pid and
+			// us sampling /proc; treat it as "not running" rather than failing
+			// the whole signal
+			minerPid = 0
+			minerTicks = 0
+		}
+	}
+
+	cpuSampleMu.Lock()
+	defer cpuSampleMu.Unlock()
+
+	prev, prevAt := lastCPUSample, lastCPUAt
+	lastCPUSample, lastCPUAt = sample, time.Now()
+	prevMinerTicks, prevMinerPid := lastMinerSample, lastMinerPid
+	lastMinerSample, lastMinerPid = minerTicks, minerPid
+
+	if prevAt.IsZero() || sample.total <= prev.total {
+		return 0, errSignalUnsupported
+	}
+
+	totalDelta := sample.total - prev.total
+	idleDelta := sample.idle - prev.idle
+	busyDelta := totalDelta - idleDelta
+
+	var minerDelta uint64
+	if minerPid != 0 && minerPid == prevMinerPid && minerTicks >= prevMinerTicks {
+		minerDelta = minerTicks - prevMinerTicks
+	}
+	if minerDelta > busyDelta {
+		minerDelta = busyDelta
+	}
+
+	foreign := float64(busyDelta-minerDelta) / float64(totalDelta) * 100
+	if foreign < 0 {
+		foreign = 0
+	}
+	return foreign, nil
+}
+
+// readProcessCPUTicks reads the utime+stime fields (in clock ticks, the same
+// unit /proc/stat reports) from /proc/<pid>/stat
+func readProcessCPUTicks(pid int) (uint64, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The process name field (comm) is parenthesized and may itself contain
+	// spaces or parens, so split on the closing paren rather than by field
+	// index
+	end := strings.LastIndex(string(data), ")")
+	if end == -1 {
+		return 0, errors.New("unexpected /proc/<pid>/stat format")
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	// After "pid (comm)", field 0 is state, so utime/stime are fields 11/12
+	// (1-indexed as per proc(5), i.e. indexes 11 and 12 once state is index 0)
+	if len(fields) < 14 {
+		return 0, errors.New("unexpected /proc/<pid>/stat format")
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return utime + stime, nil
+}
+
+// readAggregateCPUSample parses the first "cpu" line of /proc/stat, which
+// sums every core
+func readAggregateCPUSample() (cpuSample, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuSample{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return cpuSample{}, errors.New("empty /proc/stat")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return cpuSample{}, errors.New("unexpected /proc/stat format")
+	}
+
+	var total uint64
+	values := make([]uint64, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return cpuSample{}, err
+		}
+		values = append(values, v)
+		total += v
+	}
+	// user nice system idle iowait irq softirq [steal ...]; idle is index 3
+	idle := values[3]
+	return cpuSample{idle: idle, total: total}, nil
+}
@@ -0,0 +1,156 @@
+// Package stats keeps rolling hashrate and share statistics for the running
+// miner backend, so the front-end can draw a proper graph instead of relying
+// on a once-a-minute boolean flag
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// retention is how far back Recorder keeps individual hashrate samples. It
+// only needs to cover the longest rolling average (1 hour)
+const retention = time.Hour
+
+// sample is a single instantaneous hashrate reading
+type sample struct {
+	at       time.Time
+	hashrate float64
+}
+
+// Totals are the lifetime counters that survive a miner or GUI restart
+type Totals struct {
+	SharesAccepted uint64
+	SharesRejected uint64
+}
+
+// Summary is the rolling snapshot pushed to the front-end as miner_stats_summary
+// and logged every 20 seconds
+type Summary struct {
+	Hashrate1m      float64   `json:"hashrate1m"`
+	Hashrate10m     float64   `json:"hashrate10m"`
+	Hashrate1h      float64   `json:"hashrate1h"`
+	ThreadHashrates []float64 `json:"threadHashrates"`
+	SharesAccepted  uint64    `json:"sharesAccepted"`
+	SharesRejected  uint64    `json:"sharesRejected"`
+	RejectPercent   float64   `json:"rejectPercent"`
+}
+
+// Recorder accumulates hashrate samples and share counts for the currently
+// configured backend
+type Recorder struct {
+	mu sync.Mutex
+
+	samples         []sample
+	threadHashrates []float64
+
+	// lastSharesAccepted/lastSharesRejected are the most recent cumulative
+	// counters reported by the backend, used to derive per-sample deltas
+	lastSharesAccepted int
+	lastSharesRejected int
+
+	lifetime Totals
+}
+
+// NewRecorder creates a Recorder seeded with lifetime totals persisted from a
+// previous run
+func NewRecorder(lifetime Totals) *Recorder {
+	return &Recorder{lifetime: lifetime}
+}
+
+// Sample records a single hashrate reading from the miningStatsTicker cadence,
+// along with the backend's current per-thread hashrates and cumulative share
+// counts
+func (r *Recorder) Sample(hashrate float64, threadHashrates []float64, sharesAccepted, sharesRejected int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.samples = append(r.samples, sample{at: now, hashrate: hashrate})
+	r.trim(now)
+
+	r.threadHashrates = threadHashrates
+	r.lifetime.SharesAccepted += delta(sharesAccepted, &r.lastSharesAccepted)
+	r.lifetime.SharesRejected += delta(sharesRejected, &r.lastSharesRejected)
+}
+
+// delta returns how much the backend's cumulative counter grew since the
+// last sample, treating a drop (the backend process restarted) as a fresh
+// count rather than a negative delta
+func delta(current int, last *int) uint64 {
+	d := current - *last
+	if d < 0 {
+		d = current
+	}
+	*last = current
+	return uint64(d)
+}
+
+// trim drops samples older than retention
+func (r *Recorder) trim(now time.Time) {
+	cutoff := now.Add(-retention)
+	i := 0
+	for ; i < len(r.samples); i++ {
+		if r.samples[i].at.After(cutoff) {
+			break
+		}
+	}
+	r.samples = r.samples[i:]
+}
+
+// Latest returns the most recent instantaneous hashrate sample, or 0 if none
+// have been recorded yet
+func (r *Recorder) Latest() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.samples) == 0 {
+		return 0
+	}
+	return r.samples[len(r.samples)-1].hashrate
+}
+
+// average returns the mean hashrate over the last window, 0 if there are no
+// samples in range
+func (r *Recorder) average(window time.Duration) float64 {
+	cutoff := time.Now().Add(-window)
+	var sum float64
+	var count int
+	for i := len(r.samples) - 1; i >= 0; i-- {
+		if r.samples[i].at.Before(cutoff) {
+			break
+		}
+		sum += r.samples[i].hashrate
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// Summary returns the current rolling averages, per-thread hashrates and
+// lifetime share totals
+func (r *Recorder) Summary() Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := Summary{
+		Hashrate1m:      r.average(time.Minute),
+		Hashrate10m:     r.average(10 * time.Minute),
+		Hashrate1h:      r.average(time.Hour),
+		ThreadHashrates: append([]float64{}, r.threadHashrates...),
+		SharesAccepted:  r.lifetime.SharesAccepted,
+		SharesRejected:  r.lifetime.SharesRejected,
+	}
+	if total := summary.SharesAccepted + summary.SharesRejected; total > 0 {
+		summary.RejectPercent = float64(summary.SharesRejected) / float64(total) * 100
+	}
+	return summary
+}
+
+// Totals returns the lifetime counters, for persisting on Config
+func (r *Recorder) Totals() Totals {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lifetime
+}
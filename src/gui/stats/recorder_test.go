@@ -0,0 +1,66 @@
+package stats
+
+import "testing"
+
+func TestDelta(t *testing.T) {
+	last := 10
+	if got := delta(15, &last); got != 5 {
+		t.Errorf("delta(15, &10) = %d, want 5", got)
+	}
+	if last != 15 {
+		t.Errorf("delta did not update last: got %d, want 15", last)
+	}
+
+	// A drop means the backend process restarted and its counters reset;
+	// the whole new value counts as fresh shares, not a negative delta
+	last = 100
+	if got := delta(3, &last); got != 3 {
+		t.Errorf("delta(3, &100) = %d, want 3 (counter reset treated as fresh)", got)
+	}
+}
+
+func TestSummaryRejectPercent(t *testing.T) {
+	r := NewRecorder(Totals{})
+	r.Sample(1000, nil, 90, 10)
+
+	summary := r.Summary()
+	if summary.SharesAccepted != 90 || summary.SharesRejected != 10 {
+		t.Fatalf("Summary() shares = %d/%d, want 90/10", summary.SharesAccepted, summary.SharesRejected)
+	}
+	if summary.RejectPercent != 10 {
+		t.Errorf("Summary().RejectPercent = %v, want 10", summary.RejectPercent)
+	}
+}
+
+func TestSummaryNoSamplesYet(t *testing.T) {
+	r := NewRecorder(Totals{})
+	summary := r.Summary()
+	if summary.Hashrate1m != 0 || summary.RejectPercent != 0 {
+		t.Errorf("Summary() on a fresh Recorder = %+v, want all zero", summary)
+	}
+}
+
+func TestTotalsAccumulateAcrossSamples(t *testing.T) {
+	r := NewRecorder(Totals{SharesAccepted: 5})
+	r.Sample(100, nil, 2, 1)
+	r.Sample(100, nil, 4, 3)
+
+	totals := r.Totals()
+	if totals.SharesAccepted != 9 {
+		t.Errorf("Totals().SharesAccepted = %d, want 9 (5 seeded + 2 + 2 delta)", totals.SharesAccepted)
+	}
+	if totals.SharesRejected != 3 {
+		t.Errorf("Totals().SharesRejected = %d, want 3 (1 + 2 delta)", totals.SharesRejected)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	r := NewRecorder(Totals{})
+	if got := r.Latest(); got != 0 {
+		t.Errorf("Latest() on a fresh Recorder = %v, want 0", got)
+	}
+	r.Sample(123.4, nil, 0, 0)
+	if got := r.Latest(); got != 123.4 {
+		t.Errorf("Latest() = %v, want 123.4", got)
+	}
+}